@@ -1,32 +1,37 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
-)
 
-type Block struct {
-	Index      int      `json:"index"`
-	Timestamp  string   `json:"timestamp"`
-	Txs        []string `json:"txs"`        // transactions as strings
-	MerkleRoot string   `json:"merkleRoot"` // will be proper merkle root later
-	PrevHash   string   `json:"prevHash"`
-	Hash       string   `json:"hash"`
-	Nonce      int      `json:"nonce"`
-}
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/p2p"
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/protocol"
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/store"
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/wallet"
+)
 
 type Blockchain struct {
-	Name        string   `json:"name"`
-	Chain       []Block  `json:"chain"`
-	PendingTxs  []string `json:"pendingTxs"`
-	Difficulty  int      `json:"difficulty"`
+	Name             string                 `json:"name"`
+	Chain            []protocol.Block       `json:"chain"`
+	PendingTxs       []protocol.Transaction `json:"pendingTxs"`
+	Difficulty       int                    `json:"difficulty"`
+	TargetBlockTime  time.Duration          `json:"targetBlockTime"`
+	RetargetInterval int                    `json:"retargetInterval"`
+
+	store     store.BlockStore
+	index     *protocol.BlockIndex
+	orphans   *protocol.OrphanManage
+	validator protocol.Validator
+
+	peers  *p2p.Registry
+	txSeen *p2p.SeenSet
+	headCh chan protocol.Block // new chain head, for the gossip broadcaster
 }
 
 var (
@@ -35,7 +40,7 @@ var (
 )
 
 type addTxRequest struct {
-	Tx string `json:"tx"`
+	Tx protocol.Transaction `json:"tx"`
 }
 
 type messageResponse struct {
@@ -47,104 +52,143 @@ type searchResult struct {
 	Tx         string `json:"tx"`
 }
 
-// calculateHash hashes block header fields (not the whole block JSON)
-func calculateHash(index int, timestamp string, merkleRoot string, prevHash string, nonce int) string {
-	record := fmt.Sprintf("%d|%s|%s|%s|%d", index, timestamp, merkleRoot, prevHash, nonce)
-	sum := sha256.Sum256([]byte(record))
-	return hex.EncodeToString(sum[:])
-}
-
-// merkleRoot computes a Merkle root for the given transactions.
-//
-// Rules:
-// - Each tx string is hashed with SHA-256 to form the leaf level.
-// - Parent nodes are SHA-256(left || right).
-// - If a level has an odd number of nodes, the last node is duplicated.
-// - If there are no transactions, the Merkle root is SHA-256(""), i.e. the hash of empty bytes.
-func merkleRoot(txs []string) string {
-	// Empty tx list: define root as hash of empty bytes
-	if len(txs) == 0 {
-		sum := sha256.Sum256([]byte{})
-		return hex.EncodeToString(sum[:])
-	}
-
-	// Build leaf level
-	level := make([][]byte, 0, len(txs))
-	for _, tx := range txs {
-		h := sha256.Sum256([]byte(tx))
-		b := make([]byte, len(h[:]))
-		copy(b, h[:])
-		level = append(level, b)
-	}
-
-	// Build tree up to the root
-	for len(level) > 1 {
-		// If odd, duplicate last
-		if len(level)%2 == 1 {
-			dup := make([]byte, len(level[len(level)-1]))
-			copy(dup, level[len(level)-1])
-			level = append(level, dup)
-		}
-
-		next := make([][]byte, 0, len(level)/2)
-		for i := 0; i < len(level); i += 2 {
-			combined := append(level[i], level[i+1]...)
-			sum := sha256.Sum256(combined)
-			b := make([]byte, len(sum[:]))
-			copy(b, sum[:])
-			next = append(next, b)
-		}
-		level = next
-	}
+type newWalletResponse struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+}
 
-	return hex.EncodeToString(level[0])
+type signTxRequest struct {
+	PrivateKey string `json:"privateKey"`
+	To         string `json:"to"`
+	Amount     uint64 `json:"amount"`
+	Nonce      uint64 `json:"nonce"`
 }
 
-func newGenesisBlock(roll string) Block {
-	txs := []string{roll} // REQUIRED: first tx in genesis = roll number
-	ts := time.Now().UTC().Format(time.RFC3339)
-	mr := merkleRoot(txs)
+type addPeerRequest struct {
+	Addr string `json:"addr"`
+}
 
-	gen := Block{
-		Index:      0,
-		Timestamp:  ts,
-		Txs:        txs,
-		MerkleRoot: mr,
-		PrevHash:   "0",
-		Nonce:      0,
-	}
-	gen.Hash = calculateHash(gen.Index, gen.Timestamp, gen.MerkleRoot, gen.PrevHash, gen.Nonce)
-	return gen
+type txGossipRequest struct {
+	Tx protocol.Transaction `json:"tx"`
 }
 
-func NewBlockchain(name string, roll string, difficulty int) *Blockchain {
+// NewBlockchain loads the chain from bs if it already has a head, otherwise
+// seeds it with a fresh genesis block and persists that. targetBlockTime and
+// retargetInterval configure difficulty retargeting; pass retargetInterval
+// <= 0 to keep a fixed difficulty.
+func NewBlockchain(name string, roll string, difficulty int, targetBlockTime time.Duration, retargetInterval int, bs store.BlockStore) (*Blockchain, error) {
 	if difficulty <= 0 {
 		difficulty = 3 // sensible default; PoW step will use this
 	}
+
+	idx := protocol.NewBlockIndex()
 	bc := &Blockchain{
-		Name:       name,
-		Chain:      []Block{newGenesisBlock(roll)},
-		PendingTxs: make([]string, 0),
-		Difficulty: difficulty,
+		Name:             name,
+		PendingTxs:       make([]protocol.Transaction, 0),
+		Difficulty:       difficulty,
+		TargetBlockTime:  targetBlockTime,
+		RetargetInterval: retargetInterval,
+		store:            bs,
+		index:            idx,
+		orphans:          protocol.NewOrphanManage(),
+		peers:            p2p.NewRegistry(),
+		txSeen:           p2p.NewSeenSet(),
+		headCh:           make(chan protocol.Block, 16),
+	}
+	bc.validator = protocol.DefaultValidator{
+		Difficulty:       difficulty,
+		TargetBlockTime:  targetBlockTime,
+		RetargetInterval: retargetInterval,
+	}
+
+	head, err := bs.Head()
+	if errors.Is(err, store.ErrNotFound) {
+		gen := protocol.NewGenesisBlock(roll, difficulty)
+		if err := bs.SaveBlock(gen); err != nil {
+			return nil, fmt.Errorf("save genesis block: %w", err)
+		}
+		bc.Chain = []protocol.Block{gen}
+		idx.Add(gen)
+		return bc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read chain head: %w", err)
 	}
-	return bc
+
+	chain := make([]protocol.Block, 0, head.Index+1)
+	if err := bs.Iterate(func(b protocol.Block) bool {
+		chain = append(chain, b)
+		idx.Add(b)
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("load chain: %w", err)
+	}
+	bc.Chain = chain
+	bc.Difficulty = chain[len(chain)-1].Difficulty
+	bc.validator = protocol.DefaultValidator{
+		Difficulty:       bc.Difficulty,
+		TargetBlockTime:  targetBlockTime,
+		RetargetInterval: retargetInterval,
+	}
+	return bc, nil
 }
 
-// AddTransaction adds a non-empty transaction string to the pending pool.
-func (bc *Blockchain) AddTransaction(tx string) error {
-	tx = strings.TrimSpace(tx)
-	if tx == "" {
-		return errors.New("transaction cannot be empty")
+// AddTransaction verifies tx's signature and nonce and, if both check out,
+// adds it to the pending pool.
+func (bc *Blockchain) AddTransaction(tx protocol.Transaction) error {
+	if tx.From == "" || tx.To == "" {
+		return errors.New("transaction must have a from and to address")
+	}
+	if !tx.VerifySignature() {
+		return errors.New("transaction signature does not verify")
 	}
+
+	want := bc.nextNonce(tx.From)
+	if tx.Nonce != want {
+		return fmt.Errorf("invalid nonce: expected %d, got %d", want, tx.Nonce)
+	}
+
 	bc.PendingTxs = append(bc.PendingTxs, tx)
 	return nil
 }
 
+// nextNonce returns the nonce the next transaction from sender must use:
+// one more than the highest nonce seen from them on the chain or still
+// pending, or 0 if they haven't transacted yet.
+func (bc *Blockchain) nextNonce(sender string) uint64 {
+	var highest uint64
+	seen := false
+
+	consider := func(n uint64) {
+		if !seen || n > highest {
+			highest, seen = n, true
+		}
+	}
+
+	for _, b := range bc.Chain {
+		for _, tx := range b.Transactions {
+			if tx.From == sender {
+				consider(tx.Nonce)
+			}
+		}
+	}
+	for _, tx := range bc.PendingTxs {
+		if tx.From == sender {
+			consider(tx.Nonce)
+		}
+	}
+
+	if !seen {
+		return 0
+	}
+	return highest + 1
+}
+
 // BuildNextBlock constructs the next block from pending transactions (NOT mined yet).
 // It clears the pending pool after building the block.
-func (bc *Blockchain) BuildNextBlock() (Block, error) {
+func (bc *Blockchain) BuildNextBlock() (protocol.Block, error) {
 	if len(bc.PendingTxs) == 0 {
-		return Block{}, errors.New("no pending transactions to put in a block")
+		return protocol.Block{}, errors.New("no pending transactions to put in a block")
 	}
 
 	prev := bc.Chain[len(bc.Chain)-1]
@@ -152,19 +196,29 @@ func (bc *Blockchain) BuildNextBlock() (Block, error) {
 	ts := time.Now().UTC().Format(time.RFC3339)
 
 	// copy pending txs into block
-	txs := make([]string, len(bc.PendingTxs))
+	txs := make([]protocol.Transaction, len(bc.PendingTxs))
 	copy(txs, bc.PendingTxs)
 
-	mr := merkleRoot(txs)
-	b := Block{
-		Index:      index,
-		Timestamp:  ts,
-		Txs:        txs,
-		MerkleRoot: mr,
-		PrevHash:   prev.Hash,
-		Nonce:      0,
+	// JSON-string projection for backward compat with clients that only
+	// understand Block.Txs.
+	txStrs := make([]string, len(txs))
+	for i, tx := range txs {
+		txStrs[i] = tx.String()
 	}
-	b.Hash = calculateHash(b.Index, b.Timestamp, b.MerkleRoot, b.PrevHash, b.Nonce)
+
+	mr := protocol.MerkleRootTxs(txs)
+	b := protocol.Block{
+		Index:        index,
+		Timestamp:    ts,
+		Txs:          txStrs,
+		Transactions: txs,
+		MerkleRoot:   mr,
+		PrevHash:     prev.Hash,
+		Difficulty:   bc.Difficulty,
+		Nonce:        0,
+	}
+	b.Hash = protocol.CalculateHash(b.Index, b.Timestamp, b.MerkleRoot, b.PrevHash, b.Difficulty, b.Nonce)
+	b.Bloom = protocol.BlockBloom(b)
 
 	// clear pending after building
 	bc.PendingTxs = bc.PendingTxs[:0]
@@ -172,43 +226,124 @@ func (bc *Blockchain) BuildNextBlock() (Block, error) {
 	return b, nil
 }
 
-// AppendBlock appends a block to the chain (no validation yet; we will add validation later).
-func (bc *Blockchain) AppendBlock(b Block) {
+// AppendBlock appends a block to the chain, persisting it to the store and
+// recording it in the index. If b doesn't extend the current tip, it's
+// buffered as an orphan instead (no validation yet; that arrives later). A
+// block already in the index is accepted silently, since gossip can deliver
+// the same block more than once.
+func (bc *Blockchain) AppendBlock(b protocol.Block) error {
+	if _, ok := bc.index.Get(b.Hash); ok {
+		return nil
+	}
+
+	tip := bc.Chain[len(bc.Chain)-1]
+	if b.PrevHash != tip.Hash {
+		bc.orphans.Add(b)
+		return nil
+	}
+	return bc.commitBlock(b)
+}
+
+// commitBlock validates b against the current tip, persists it, appends it
+// to the in-memory chain, and then replays any orphans that were waiting
+// on it.
+func (bc *Blockchain) commitBlock(b protocol.Block) error {
+	tip := bc.Chain[len(bc.Chain)-1]
+	if err := bc.validator.ValidateHeader(tip, b); err != nil {
+		protocol.ReportBadBlock(b, err)
+		return err
+	}
+	if err := bc.validator.ValidateBody(b); err != nil {
+		protocol.ReportBadBlock(b, err)
+		return err
+	}
+
+	if err := bc.store.SaveBlock(b); err != nil {
+		return fmt.Errorf("persist block %d: %w", b.Index, err)
+	}
 	bc.Chain = append(bc.Chain, b)
+	bc.index.Add(b)
+	bc.retarget()
+	bc.announceHead(b)
+
+	for _, child := range bc.orphans.Children(b.Hash) {
+		if err := bc.commitBlock(child); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func hasLeadingZeros(hash string, difficulty int) bool {
-	if difficulty <= 0 {
-		return true
+// announceHead pushes b onto headCh for the gossip broadcaster to pick up,
+// without blocking the caller if nothing is listening yet.
+func (bc *Blockchain) announceHead(b protocol.Block) {
+	select {
+	case bc.headCh <- b:
+	default:
 	}
-	prefix := strings.Repeat("0", difficulty)
-	return strings.HasPrefix(hash, prefix)
 }
 
-// MineBlock performs Proof-of-Work on a block by incrementing the nonce until
-// the hash has `difficulty` leading zeros.
-func MineBlock(b Block, difficulty int) Block {
-	for {
-		b.Hash = calculateHash(b.Index, b.Timestamp, b.MerkleRoot, b.PrevHash, b.Nonce)
-		if hasLeadingZeros(b.Hash, difficulty) {
-			return b
+// replaceChain swaps in newChain wholesale — used when a peer's chain turns
+// out to be longer and validates. Every block is re-persisted (the store
+// keys on index and hash, so this just overwrites what's there) and the
+// index is rebuilt from scratch.
+func (bc *Blockchain) replaceChain(newChain []protocol.Block) {
+	bc.Chain = newChain
+	bc.index = protocol.NewBlockIndex()
+	for _, b := range newChain {
+		bc.index.Add(b)
+		if err := bc.store.SaveBlock(b); err != nil {
+			fmt.Println("store error while replacing chain:", err)
 		}
-		b.Nonce++
+	}
+
+	tip := newChain[len(newChain)-1]
+	bc.Difficulty = tip.Difficulty
+	bc.validator = protocol.DefaultValidator{
+		Difficulty:       bc.Difficulty,
+		TargetBlockTime:  bc.TargetBlockTime,
+		RetargetInterval: bc.RetargetInterval,
+	}
+	bc.announceHead(tip)
+}
+
+// retarget adjusts bc.Difficulty once the chain has grown by a full
+// RetargetInterval since the last adjustment, based on how long that
+// window actually took versus TargetBlockTime * RetargetInterval. The new
+// difficulty only takes effect for blocks built after this point; blocks
+// already on the chain keep the difficulty they were mined at.
+func (bc *Blockchain) retarget() {
+	if bc.RetargetInterval <= 0 {
+		return
+	}
+
+	next, ok := protocol.RetargetAt(bc.Chain, len(bc.Chain)-1, bc.TargetBlockTime, bc.RetargetInterval)
+	if !ok {
+		return
+	}
+
+	bc.Difficulty = next
+	bc.validator = protocol.DefaultValidator{
+		Difficulty:       bc.Difficulty,
+		TargetBlockTime:  bc.TargetBlockTime,
+		RetargetInterval: bc.RetargetInterval,
 	}
 }
 
 // MineNextBlock builds the next block from pending txs, mines it, and appends it.
-func (bc *Blockchain) MineNextBlock() (Block, error) {
+func (bc *Blockchain) MineNextBlock() (protocol.Block, error) {
 	b, err := bc.BuildNextBlock()
 	if err != nil {
-		return Block{}, err
+		return protocol.Block{}, err
 	}
 
 	start := time.Now()
-	mined := MineBlock(b, bc.Difficulty)
+	mined := protocol.MineBlock(b, bc.Difficulty)
 	elapsed := time.Since(start)
 
-	bc.AppendBlock(mined)
+	if err := bc.AppendBlock(mined); err != nil {
+		return protocol.Block{}, err
+	}
 
 	fmt.Printf("\n⛏️  Mined block %d with difficulty %d in %s\n", mined.Index, bc.Difficulty, elapsed)
 	fmt.Printf("  Nonce: %d\n", mined.Nonce)
@@ -281,19 +416,32 @@ func handleAddTx(w http.ResponseWriter, r *http.Request) {
 	}
 
 	mu.Lock()
-	defer mu.Unlock()
-
 	if err := bc.AddTransaction(req.Tx); err != nil {
+		mu.Unlock()
 		writeJSON(w, http.StatusBadRequest, messageResponse{Message: err.Error()})
 		return
 	}
+	bc.txSeen.SeenOrAdd(req.Tx.Hash())
+	pending := bc.PendingTxs
+	mu.Unlock()
+
+	gossipTx(req.Tx)
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"message":    "transaction added",
-		"pendingTxs": bc.PendingTxs,
+		"pendingTxs": pending,
 	})
 }
 
+// gossipTx broadcasts tx to every known peer's /tx/gossip endpoint.
+func gossipTx(tx protocol.Transaction) {
+	raw, err := json.Marshal(txGossipRequest{Tx: tx})
+	if err != nil {
+		return
+	}
+	bc.peers.Broadcast("/tx/gossip", raw)
+}
+
 func handleMine(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
@@ -312,6 +460,219 @@ func handleMine(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, mined)
 }
 
+// handleWalletNew generates a fresh signing keypair. The private key is
+// returned directly in the response, so this is a demo convenience, not a
+// real custody model.
+func handleWalletNew(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	kp, err := wallet.Generate()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, messageResponse{Message: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newWalletResponse{
+		PublicKey:  kp.PublicKeyHex(),
+		PrivateKey: kp.PrivateKeyHex(),
+	})
+}
+
+// handleTxSign builds and signs a transaction server-side from a hex private
+// key, for demo clients that don't sign in the browser. It does not submit
+// the transaction; POST the result to /tx to add it to the pending pool.
+func handleTxSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	var req signTxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid JSON body"})
+		return
+	}
+
+	kp, err := wallet.ParsePrivateKeyHex(req.PrivateKey)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: err.Error()})
+		return
+	}
+
+	tx := protocol.Transaction{
+		From:   kp.PublicKeyHex(),
+		To:     req.To,
+		Amount: req.Amount,
+		Nonce:  req.Nonce,
+	}
+	tx.Sig = kp.Sign(tx.CanonicalBytes())
+
+	writeJSON(w, http.StatusOK, tx)
+}
+
+// handleBlock looks up a single block by hash (?hash=) or by chain index
+// (?index=), going through the in-memory index for O(1) lookups.
+func handleBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	hash := strings.TrimSpace(r.URL.Query().Get("hash"))
+	indexParam := strings.TrimSpace(r.URL.Query().Get("index"))
+	if hash == "" && indexParam == "" {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "provide ?hash= or ?index="})
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if hash != "" {
+		b, ok := bc.index.Get(hash)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, messageResponse{Message: "no block with that hash"})
+			return
+		}
+		writeJSON(w, http.StatusOK, b)
+		return
+	}
+
+	i, err := strconv.Atoi(indexParam)
+	if err != nil || i < 0 || i >= len(bc.Chain) {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid index"})
+		return
+	}
+	writeJSON(w, http.StatusOK, bc.Chain[i])
+}
+
+// handleProof returns a Merkle inclusion proof for one transaction in one
+// block: its leaf hash, the sibling path up to the root, and whether that
+// path actually verifies against the block's stored MerkleRoot.
+func handleProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	blockIndex, err := strconv.Atoi(r.URL.Query().Get("block"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid or missing ?block="})
+		return
+	}
+	txIndex, err := strconv.Atoi(r.URL.Query().Get("tx"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid or missing ?tx="})
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if blockIndex < 0 || blockIndex >= len(bc.Chain) {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "block index out of range"})
+		return
+	}
+	b := bc.Chain[blockIndex]
+
+	var tree *protocol.MerkleTree
+	if len(b.Transactions) > 0 {
+		tree = protocol.NewMerkleTreeTxs(b.Transactions)
+	} else {
+		tree = protocol.NewMerkleTree(b.Txs)
+	}
+
+	leafHash, err := tree.LeafHash(txIndex)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: err.Error()})
+		return
+	}
+	proof, err := tree.Proof(txIndex)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"block":      blockIndex,
+		"tx":         txIndex,
+		"leafHash":   leafHash,
+		"proof":      proof,
+		"merkleRoot": b.MerkleRoot,
+		"valid":      protocol.VerifyMerkleProof(leafHash, proof, b.MerkleRoot),
+	})
+}
+
+// handleValidate re-validates the entire chain and returns a per-block report.
+func handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	reports := protocol.ValidateChainReport(bc.validator, bc.Chain)
+	valid := true
+	for _, rep := range reports {
+		if !rep.Valid {
+			valid = false
+			break
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"valid":  valid,
+		"blocks": reports,
+	})
+}
+
+// handleDifficulty returns the current mining difficulty plus the
+// per-block difficulty history recorded on the chain itself.
+func handleDifficulty(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	history := make([]map[string]int, 0, len(bc.Chain))
+	for _, b := range bc.Chain {
+		history = append(history, map[string]int{"index": b.Index, "difficulty": b.Difficulty})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"current": bc.Difficulty,
+		"history": history,
+	})
+}
+
+// blockPassesBloom reports whether b's Bloom filter has every one of tokens
+// set, i.e. whether b is a candidate worth scanning for them.
+func blockPassesBloom(b protocol.Block, tokens []string) bool {
+	n := len(b.Txs)
+	if len(b.Transactions) > 0 {
+		n = len(b.Transactions)
+	}
+	for _, tok := range tokens {
+		if !protocol.BloomTest(b.Bloom, n, []byte(tok)) {
+			return false
+		}
+	}
+	return true
+}
+
+// handleSearch finds transactions whose string form contains q. mode
+// controls how the per-block Bloom filter is used:
+//   - scan:  ignore the Bloom filter and substring-scan every block (baseline)
+//   - bloom: only test the Bloom filter; return candidate blocks, no scan
+//   - both:  (default) skip blocks the Bloom filter rules out, scan the rest
 func handleSearch(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
@@ -325,11 +686,38 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 	}
 	qLower := strings.ToLower(q)
 
+	mode := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("mode")))
+	if mode != "bloom" && mode != "scan" {
+		mode = "both"
+	}
+	tokens := protocol.Tokenize(q)
+
 	mu.Lock()
 	defer mu.Unlock()
 
 	results := make([]searchResult, 0)
+	candidates := make([]int, 0)
+	hits, misses := 0, 0
+
 	for _, b := range bc.Chain {
+		passes := true
+		if mode != "scan" {
+			passes = blockPassesBloom(b, tokens)
+			if passes {
+				hits++
+			} else {
+				misses++
+			}
+		}
+		if !passes {
+			continue
+		}
+
+		if mode == "bloom" {
+			candidates = append(candidates, b.Index)
+			continue
+		}
+
 		for _, tx := range b.Txs {
 			if strings.Contains(strings.ToLower(tx), qLower) {
 				results = append(results, searchResult{BlockIndex: b.Index, Tx: tx})
@@ -337,39 +725,209 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"query":   q,
-		"count":   len(results),
-		"results": results,
-	})
+	resp := map[string]any{
+		"query":        q,
+		"mode":         mode,
+		"filterHits":   hits,
+		"filterMisses": misses,
+	}
+	if mode == "bloom" {
+		resp["count"] = len(candidates)
+		resp["candidateBlocks"] = candidates
+	} else {
+		resp["count"] = len(results)
+		resp["results"] = results
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handlePeers registers a peer address (POST {"addr":"host:port"}) or lists
+// the peers known so far (GET).
+func handlePeers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req addPeerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid JSON body"})
+			return
+		}
+		bc.peers.Add(req.Addr)
+		writeJSON(w, http.StatusOK, map[string]any{"peers": bc.peers.List()})
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"peers": bc.peers.List()})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+	}
+}
+
+// handleBlocksReceive accepts a block gossiped in from a peer and tries to
+// append it the same way a locally mined block would be appended.
+func handleBlocksReceive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	var b protocol.Block
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid JSON body"})
+		return
+	}
+
+	mu.Lock()
+	err := bc.AppendBlock(b)
+	mu.Unlock()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, messageResponse{Message: "block accepted"})
+}
+
+// handleTxGossip accepts a transaction gossiped in from a peer, adds it to
+// the pending pool if it's new, and re-broadcasts it so it keeps spreading
+// across the network. seen-set dedup stops it from looping forever.
+func handleTxGossip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, messageResponse{Message: "method not allowed"})
+		return
+	}
+
+	var req txGossipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: "invalid JSON body"})
+		return
+	}
+
+	mu.Lock()
+	if bc.txSeen.SeenOrAdd(req.Tx.Hash()) {
+		mu.Unlock()
+		writeJSON(w, http.StatusOK, messageResponse{Message: "already seen"})
+		return
+	}
+	err := bc.AddTransaction(req.Tx)
+	mu.Unlock()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, messageResponse{Message: err.Error()})
+		return
+	}
+
+	gossipTx(req.Tx)
+	writeJSON(w, http.StatusOK, messageResponse{Message: "transaction added"})
+}
+
+// broadcastHeads drains bc.headCh and forwards every new head block to all
+// known peers. Runs for the lifetime of the process.
+func broadcastHeads() {
+	for b := range bc.headCh {
+		raw, err := json.Marshal(b)
+		if err != nil {
+			continue
+		}
+		bc.peers.Broadcast("/blocks/receive", raw)
+	}
+}
+
+// syncWithPeers pulls /chain from every known peer and, if a peer's chain is
+// both longer and valid, replaces the local chain with it (longest-valid-
+// chain rule). Runs periodically for the lifetime of the process.
+func syncWithPeers() {
+	mu.Lock()
+	peers := bc.peers.List()
+	mu.Unlock()
+
+	for _, addr := range peers {
+		raw, err := p2p.Get(addr, "/chain")
+		if err != nil {
+			continue
+		}
+
+		var remote struct {
+			Chain []protocol.Block `json:"chain"`
+		}
+		if err := json.Unmarshal(raw, &remote); err != nil || len(remote.Chain) == 0 {
+			continue
+		}
+
+		mu.Lock()
+		if len(remote.Chain) > len(bc.Chain) {
+			if err := bc.validator.ValidateChain(remote.Chain); err == nil {
+				bc.replaceChain(remote.Chain)
+			}
+		}
+		mu.Unlock()
+	}
 }
 
 func main() {
 	name := "Talal Nadeem"
 	roll := "22L-6679"
 
+	bs, err := store.Open("./data/chaindb")
+	if err != nil {
+		fmt.Println("store error:", err)
+		return
+	}
+	defer bs.Close()
+
 	// Difficulty is number of leading zeros required in the hash.
 	// You can increase this later; 3 is a reasonable starting value for demos.
-	bc = NewBlockchain(name, roll, 3)
+	// Retarget every 10 blocks to aim for one block roughly every 5 seconds.
+	bc, err = NewBlockchain(name, roll, 3, 5*time.Second, 10, bs)
+	if err != nil {
+		fmt.Println("blockchain init error:", err)
+		return
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", withCORS(handleHealth))
 	mux.HandleFunc("/chain", withCORS(handleChain))
 	mux.HandleFunc("/pending", withCORS(handlePending))
 	mux.HandleFunc("/tx", withCORS(handleAddTx))
+	mux.HandleFunc("/wallet/new", withCORS(handleWalletNew))
+	mux.HandleFunc("/tx/sign", withCORS(handleTxSign))
 	mux.HandleFunc("/mine", withCORS(handleMine))
+	mux.HandleFunc("/block", withCORS(handleBlock))
+	mux.HandleFunc("/proof", withCORS(handleProof))
+	mux.HandleFunc("/validate", withCORS(handleValidate))
+	mux.HandleFunc("/difficulty", withCORS(handleDifficulty))
 	mux.HandleFunc("/search", withCORS(handleSearch))
+	mux.HandleFunc("/peers", withCORS(handlePeers))
+	mux.HandleFunc("/blocks/receive", withCORS(handleBlocksReceive))
+	mux.HandleFunc("/tx/gossip", withCORS(handleTxGossip))
+
+	go broadcastHeads()
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			syncWithPeers()
+		}
+	}()
 
 	addr := ":8080"
 	fmt.Println("✅ Server running at http://localhost" + addr)
 	fmt.Println("Endpoints:")
 	fmt.Println("  GET  /chain")
 	fmt.Println("  GET  /pending")
-	fmt.Println("  POST /tx      {\"tx\":\"Alice -> Bob : 5\"}")
+	fmt.Println("  POST /tx      {\"tx\":{...signed transaction...}}")
+	fmt.Println("  POST /wallet/new")
+	fmt.Println("  POST /tx/sign {\"privateKey\":\"…\",\"to\":\"…\",\"amount\":5,\"nonce\":0}")
 	fmt.Println("  POST /mine")
-	fmt.Println("  GET  /search?q=Bob")
+	fmt.Println("  GET  /block?hash=…")
+	fmt.Println("  GET  /block?index=…")
+	fmt.Println("  GET  /proof?block=…&tx=…")
+	fmt.Println("  POST /validate")
+	fmt.Println("  GET  /difficulty")
+	fmt.Println("  GET  /search?q=Bob&mode=bloom|scan|both")
+	fmt.Println("  POST /peers   {\"addr\":\"host:port\"}")
+	fmt.Println("  GET  /peers")
+	fmt.Println("  POST /blocks/receive")
+	fmt.Println("  POST /tx/gossip")
 
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		fmt.Println("server error:", err)
 	}
-}
\ No newline at end of file
+}
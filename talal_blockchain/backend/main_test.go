@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/protocol"
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/store"
+)
+
+func newTestBlockchain(t *testing.T) *Blockchain {
+	t.Helper()
+
+	bs, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { bs.Close() })
+
+	bc, err := NewBlockchain("test-chain", "22L-6679", 1, 0, 0, bs)
+	if err != nil {
+		t.Fatalf("new blockchain: %v", err)
+	}
+	return bc
+}
+
+func signTestTx(priv ed25519.PrivateKey, pub ed25519.PublicKey, to string, amount, nonce uint64) protocol.Transaction {
+	tx := protocol.Transaction{
+		From:   hex.EncodeToString(pub),
+		To:     to,
+		Amount: amount,
+		Nonce:  nonce,
+	}
+	tx.Sig = ed25519.Sign(priv, tx.CanonicalBytes())
+	return tx
+}
+
+func TestAddTransactionAcceptsFirstNonceZero(t *testing.T) {
+	bc := newTestBlockchain(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tx := signTestTx(priv, pub, "bob", 10, 0)
+	if err := bc.AddTransaction(tx); err != nil {
+		t.Fatalf("expected first transaction at nonce 0 to be accepted, got: %v", err)
+	}
+}
+
+func TestAddTransactionRejectsStaleNonce(t *testing.T) {
+	bc := newTestBlockchain(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	first := signTestTx(priv, pub, "bob", 10, 0)
+	if err := bc.AddTransaction(first); err != nil {
+		t.Fatalf("expected first transaction to be accepted, got: %v", err)
+	}
+
+	// Reusing nonce 0 from the same sender must be rejected, whether it's an
+	// exact replay or just a stale value that no longer matches nextNonce.
+	replay := signTestTx(priv, pub, "carol", 5, 0)
+	if err := bc.AddTransaction(replay); err == nil {
+		t.Fatal("expected a duplicate/stale nonce to be rejected")
+	}
+}
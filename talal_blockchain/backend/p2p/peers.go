@@ -0,0 +1,99 @@
+// Package p2p lets a node discover peers and gossip blocks and
+// transactions to them. It only knows about addresses and raw bytes; the
+// caller decides what to broadcast and how to react to what arrives.
+package p2p
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// httpClient is shared across broadcasts so gossip doesn't pile up
+// goroutines waiting on slow or dead peers.
+var httpClient = &http.Client{Timeout: 3 * time.Second}
+
+// Registry tracks the peers this node knows about.
+type Registry struct {
+	mu    sync.Mutex
+	peers map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{peers: make(map[string]bool)}
+}
+
+// Add registers addr as a peer. It's a no-op if addr is already known.
+func (r *Registry) Add(addr string) {
+	if addr == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.peers[addr] = true
+}
+
+// List returns every known peer address.
+func (r *Registry) List() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addrs := make([]string, 0, len(r.peers))
+	for addr := range r.peers {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// Broadcast POSTs body to path on every known peer, concurrently and
+// best-effort — a dead or slow peer doesn't block the others, and errors
+// are dropped since gossip is inherently unreliable.
+func (r *Registry) Broadcast(path string, body []byte) {
+	for _, addr := range r.List() {
+		go func(addr string) {
+			resp, err := httpClient.Post("http://"+addr+path, "application/json", bytes.NewReader(body))
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+		}(addr)
+	}
+}
+
+// Get fetches path from addr, returning the response body.
+func Get(addr, path string) ([]byte, error) {
+	resp, err := httpClient.Get("http://" + addr + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SeenSet remembers ids that have already been processed, so gossip
+// messages that loop back around get dropped instead of re-broadcast
+// forever.
+type SeenSet struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func NewSeenSet() *SeenSet {
+	return &SeenSet{seen: make(map[string]bool)}
+}
+
+// SeenOrAdd reports whether id was already recorded, and records it if not.
+func (s *SeenSet) SeenOrAdd(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[id] {
+		return true
+	}
+	s.seen[id] = true
+	return false
+}
@@ -0,0 +1,141 @@
+// Package protocol holds the core chain data structures and the pure
+// functions (hashing, merkle roots, proof-of-work) that both the HTTP
+// server and the persistent store need, without pulling in either of
+// those dependencies.
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Block struct {
+	Index        int           `json:"index"`
+	Timestamp    string        `json:"timestamp"`
+	Txs          []string      `json:"txs"`          // JSON-string projection of Transactions, kept for older /chain consumers
+	Transactions []Transaction `json:"transactions"` // signed transactions; empty for blocks that only carry legacy string txs (e.g. genesis)
+	MerkleRoot   string        `json:"merkleRoot"`
+	PrevHash     string        `json:"prevHash"`
+	Hash         string        `json:"hash"`
+	Difficulty   int           `json:"difficulty"` // leading zeros required of Hash, at mine time
+	Nonce        int           `json:"nonce"`
+	Bloom        []byte        `json:"bloom,omitempty"` // Bloom filter over this block's searchable tx tokens, for /search
+}
+
+// CalculateHash hashes block header fields (not the whole block JSON).
+func CalculateHash(index int, timestamp string, merkleRoot string, prevHash string, difficulty int, nonce int) string {
+	record := fmt.Sprintf("%d|%s|%s|%s|%d|%d", index, timestamp, merkleRoot, prevHash, difficulty, nonce)
+	sum := sha256.Sum256([]byte(record))
+	return hex.EncodeToString(sum[:])
+}
+
+// MerkleRoot computes a Merkle root over plain-string transactions (used by
+// the genesis block, whose single tx is an unsigned roll-number marker).
+//
+// Rules:
+// - Each tx string is hashed with SHA-256 to form the leaf level.
+// - Parent nodes are SHA-256(left || right).
+// - If a level has an odd number of nodes, the last node is duplicated.
+// - If there are no transactions, the Merkle root is SHA-256(""), i.e. the hash of empty bytes.
+func MerkleRoot(txs []string) string {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		h := sha256.Sum256([]byte(tx))
+		leaves[i] = h[:]
+	}
+	return merkleRootFromLeaves(leaves)
+}
+
+// MerkleRootTxs computes a Merkle root over signed transactions, hashing
+// each tx's canonical bytes rather than a raw string. Same odd-duplication
+// rule as MerkleRoot, so proofs built against either kind of block verify
+// the same way.
+func MerkleRootTxs(txs []Transaction) string {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		h := sha256.Sum256(tx.CanonicalBytes())
+		leaves[i] = h[:]
+	}
+	return merkleRootFromLeaves(leaves)
+}
+
+func merkleRootFromLeaves(leaves [][]byte) string {
+	// No leaves: define root as hash of empty bytes
+	if len(leaves) == 0 {
+		sum := sha256.Sum256([]byte{})
+		return hex.EncodeToString(sum[:])
+	}
+
+	level := make([][]byte, len(leaves))
+	for i, h := range leaves {
+		b := make([]byte, len(h))
+		copy(b, h)
+		level[i] = b
+	}
+
+	// Build tree up to the root
+	for len(level) > 1 {
+		// If odd, duplicate last
+		if len(level)%2 == 1 {
+			dup := make([]byte, len(level[len(level)-1]))
+			copy(dup, level[len(level)-1])
+			level = append(level, dup)
+		}
+
+		next := make([][]byte, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			combined := append(level[i], level[i+1]...)
+			sum := sha256.Sum256(combined)
+			b := make([]byte, len(sum[:]))
+			copy(b, sum[:])
+			next = append(next, b)
+		}
+		level = next
+	}
+
+	return hex.EncodeToString(level[0])
+}
+
+func NewGenesisBlock(roll string, difficulty int) Block {
+	txs := []string{roll} // REQUIRED: first tx in genesis = roll number
+	ts := time.Now().UTC().Format(time.RFC3339)
+	mr := MerkleRoot(txs)
+
+	gen := Block{
+		Index:      0,
+		Timestamp:  ts,
+		Txs:        txs,
+		MerkleRoot: mr,
+		PrevHash:   "0",
+		Difficulty: difficulty,
+		Nonce:      0,
+	}
+	gen.Hash = CalculateHash(gen.Index, gen.Timestamp, gen.MerkleRoot, gen.PrevHash, gen.Difficulty, gen.Nonce)
+	gen.Bloom = BlockBloom(gen)
+	return gen
+}
+
+func HasLeadingZeros(hash string, difficulty int) bool {
+	if difficulty <= 0 {
+		return true
+	}
+	prefix := strings.Repeat("0", difficulty)
+	return strings.HasPrefix(hash, prefix)
+}
+
+// MineBlock performs Proof-of-Work on a block by incrementing the nonce until
+// the hash has `difficulty` leading zeros. The difficulty used is recorded
+// on the returned block so later validation can check the PoW against it.
+func MineBlock(b Block, difficulty int) Block {
+	b.Difficulty = difficulty
+	for {
+		b.Hash = CalculateHash(b.Index, b.Timestamp, b.MerkleRoot, b.PrevHash, b.Difficulty, b.Nonce)
+		if HasLeadingZeros(b.Hash, b.Difficulty) {
+			return b
+		}
+		b.Nonce++
+	}
+}
@@ -0,0 +1,115 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// targetBloomFPR is the false-positive rate BloomParams sizes a filter for.
+const targetBloomFPR = 0.01
+
+// maxBloomHashes caps k at the number of distinct 32-bit slices a single
+// double-SHA256 digest yields (32 bytes / 4 bytes per slice = 8). Deriving
+// more than 8 indexes from one digest would just repeat earlier slices
+// verbatim, giving fewer effective hash functions than k claims and a
+// worse real false-positive rate than targetBloomFPR.
+const maxBloomHashes = 8
+
+// BloomParams returns the bit-array size and hash-function count for a
+// Bloom filter sized for n items at a 1% target false-positive rate. n is
+// floored at 1 so an empty block still gets a (trivial) usable filter. k is
+// capped at maxBloomHashes.
+func BloomParams(n int) (m int, k int) {
+	if n <= 0 {
+		n = 1
+	}
+	m = int(math.Ceil(-float64(n) * math.Log(targetBloomFPR) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	if k > maxBloomHashes {
+		k = maxBloomHashes
+	}
+	return m, k
+}
+
+// bloomIndexes derives k (<= maxBloomHashes) bit indexes (each in [0, m))
+// for item, from the k 32-bit big-endian slices of double-SHA256(item).
+func bloomIndexes(item []byte, m, k int) []int {
+	first := sha256.Sum256(item)
+	second := sha256.Sum256(first[:])
+
+	idxs := make([]int, k)
+	for i := 0; i < k; i++ {
+		slice := second[i*4 : i*4+4]
+		idxs[i] = int(binary.BigEndian.Uint32(slice)) % m
+	}
+	return idxs
+}
+
+// BuildBloom builds a Bloom filter bit-array over items, sized via
+// BloomParams(n). n is normally len(items), but callers that pad items
+// with multiple tokens per transaction pass the transaction count instead,
+// so the filter is sized for the block's tx count as intended.
+func BuildBloom(n int, items [][]byte) []byte {
+	m, k := BloomParams(n)
+	bits := make([]byte, (m+7)/8)
+	for _, item := range items {
+		for _, idx := range bloomIndexes(item, m, k) {
+			bits[idx/8] |= 1 << uint(idx%8)
+		}
+	}
+	return bits
+}
+
+// BloomTest reports whether item might have been added to a filter built by
+// BuildBloom(n, ...). n must be the same n the filter was built with.
+func BloomTest(bits []byte, n int, item []byte) bool {
+	m, k := BloomParams(n)
+	for _, idx := range bloomIndexes(item, m, k) {
+		if idx/8 >= len(bits) || bits[idx/8]&(1<<uint(idx%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Tokenize splits s into lowercase alphanumeric tokens, treating whitespace
+// and punctuation as separators. Used to build and query per-block Bloom
+// filters over searchable transaction text.
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// BlockBloom computes the Bloom filter b should carry, over the tokens of
+// its searchable transaction text (Txs, or the JSON projection of
+// Transactions for signed-tx blocks). Shared by block construction and
+// validation so the two can never drift apart.
+func BlockBloom(b Block) []byte {
+	var texts []string
+	if len(b.Transactions) > 0 {
+		texts = make([]string, len(b.Transactions))
+		for i, tx := range b.Transactions {
+			texts[i] = tx.String()
+		}
+	} else {
+		texts = b.Txs
+	}
+
+	var items [][]byte
+	for _, s := range texts {
+		for _, tok := range Tokenize(s) {
+			items = append(items, []byte(tok))
+		}
+	}
+	return BuildBloom(len(texts), items)
+}
@@ -0,0 +1,42 @@
+package protocol
+
+import "testing"
+
+func TestBloomParamsCapsHashCountAtEightSlices(t *testing.T) {
+	// n=5 would otherwise call for k=9 independent hashes, but
+	// double-SHA256 only yields 8 distinct 32-bit slices to derive from.
+	for n := 1; n <= 50; n++ {
+		_, k := BloomParams(n)
+		if k > maxBloomHashes {
+			t.Fatalf("BloomParams(%d) k = %d, want <= %d", n, k, maxBloomHashes)
+		}
+	}
+}
+
+func TestBuildBloomMatchesAddedItemsAndRejectsMost(t *testing.T) {
+	items := [][]byte{[]byte("alice"), []byte("bob"), []byte("22l-6679")}
+	bits := BuildBloom(len(items), items)
+
+	for _, item := range items {
+		if !BloomTest(bits, len(items), item) {
+			t.Errorf("BloomTest should match inserted item %q", item)
+		}
+	}
+	if BloomTest(bits, len(items), []byte("definitely-not-inserted")) {
+		t.Error("BloomTest matched an item that was never inserted")
+	}
+}
+
+func TestTokenizeLowercasesAndSplitsOnPunctuation(t *testing.T) {
+	got := Tokenize(`{"from":"Alice","to":"Bob-42"}`)
+	want := []string{"from", "alice", "to", "bob", "42"}
+
+	if len(got) != len(want) {
+		t.Fatalf("Tokenize() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Tokenize()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package protocol
+
+import (
+	"math"
+	"time"
+)
+
+// NextDifficulty retargets old based on how long a window of blocks
+// actually took to mine versus how long it was expected to take. The
+// result is clamped to old±1 so difficulty can't swing wildly between
+// retargets, and never drops below 1.
+func NextDifficulty(old int, actual, expected time.Duration) int {
+	if actual <= 0 {
+		actual = time.Nanosecond
+	}
+
+	ratio := float64(expected) / float64(actual)
+	next := int(math.Round(float64(old) * ratio))
+
+	if next > old+1 {
+		next = old + 1
+	}
+	if next < old-1 {
+		next = old - 1
+	}
+	if next < 1 {
+		next = 1
+	}
+	return next
+}
+
+// RetargetAt reports the difficulty that should take effect starting at
+// chain[i+1] if i lands on a retarget boundary, i.e. it's the
+// `interval`-th block since the last retarget and there's a full window of
+// prior blocks to measure. ok is false when i isn't a boundary, meaning
+// chain[i+1].Difficulty is expected to simply match chain[i].Difficulty.
+// This mirrors Blockchain.retarget() in main.go, which recomputes
+// bc.Difficulty right after committing boundary block i, for use on the
+// next block it mines.
+func RetargetAt(chain []Block, i int, targetBlockTime time.Duration, interval int) (difficulty int, ok bool) {
+	if interval <= 0 || i < interval || (i+1)%interval != 0 {
+		return 0, false
+	}
+
+	lastTs, err := time.Parse(time.RFC3339, chain[i].Timestamp)
+	if err != nil {
+		return 0, false
+	}
+	firstTs, err := time.Parse(time.RFC3339, chain[i-interval].Timestamp)
+	if err != nil {
+		return 0, false
+	}
+
+	actual := lastTs.Sub(firstTs)
+	expected := targetBlockTime * time.Duration(interval)
+	return NextDifficulty(chain[i-1].Difficulty, actual, expected), true
+}
@@ -0,0 +1,89 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextDifficultyClampsToOldPlusMinusOne(t *testing.T) {
+	cases := []struct {
+		name             string
+		old              int
+		actual, expected time.Duration
+		want             int
+	}{
+		{"much faster than target", 5, 1 * time.Second, 10 * time.Second, 6},
+		{"much slower than target", 5, 10 * time.Second, 1 * time.Second, 4},
+		{"on target", 5, 2 * time.Second, 2 * time.Second, 5},
+		{"never drops below 1", 1, 10 * time.Second, 1 * time.Second, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NextDifficulty(tc.old, tc.actual, tc.expected)
+			if got != tc.want {
+				t.Errorf("NextDifficulty(%d, %s, %s) = %d, want %d", tc.old, tc.actual, tc.expected, got, tc.want)
+			}
+		})
+	}
+}
+
+// mineTestChain mines n blocks on top of a genesis block, applying
+// retargeting the same way Blockchain.retarget (main.go) does: the
+// difficulty recomputed via RetargetAt right after committing boundary
+// block i takes effect starting at block i+1, not at block i itself.
+func mineTestChain(t *testing.T, n int, startDifficulty int, targetBlockTime time.Duration, interval int, gap time.Duration) []Block {
+	t.Helper()
+
+	gen := NewGenesisBlock("22L-6679", startDifficulty)
+	chain := []Block{gen}
+	difficulty := startDifficulty
+	base := time.Now().UTC()
+
+	for i := 1; i <= n; i++ {
+		prev := chain[len(chain)-1]
+		ts := base.Add(time.Duration(i) * gap).Format(time.RFC3339)
+		mr := MerkleRoot(nil)
+
+		b := Block{
+			Index:      prev.Index + 1,
+			Timestamp:  ts,
+			Txs:        []string{},
+			MerkleRoot: mr,
+			PrevHash:   prev.Hash,
+			Difficulty: difficulty,
+		}
+		b.Bloom = BlockBloom(b)
+		b = MineBlock(b, difficulty)
+		chain = append(chain, b)
+
+		if next, ok := RetargetAt(chain, len(chain)-1, targetBlockTime, interval); ok {
+			difficulty = next
+		}
+	}
+
+	return chain
+}
+
+// TestValidateChainAcrossRetargetBoundary reproduces the maintainer's repro:
+// mine a chain through a retarget boundary exactly the way main.go mines
+// and retargets, then validate it. A chain built by this repo's own mining
+// path must always validate.
+func TestValidateChainAcrossRetargetBoundary(t *testing.T) {
+	const interval = 10
+	const targetBlockTime = 2 * time.Second
+
+	chain := mineTestChain(t, 22, 1, targetBlockTime, interval, 1*time.Second)
+
+	if chain[19].Difficulty != 1 {
+		t.Fatalf("boundary block 19 difficulty = %d, want unchanged 1", chain[19].Difficulty)
+	}
+	if chain[20].Difficulty != 2 {
+		t.Fatalf("block 20 (first after the boundary) difficulty = %d, want retargeted 2", chain[20].Difficulty)
+	}
+
+	v := DefaultValidator{Difficulty: 1, TargetBlockTime: targetBlockTime, RetargetInterval: interval}
+	if err := v.ValidateChain(chain); err != nil {
+		t.Fatalf("self-mined chain crossing a retarget boundary should validate, got: %v", err)
+	}
+}
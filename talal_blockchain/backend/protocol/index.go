@@ -0,0 +1,84 @@
+package protocol
+
+import "sync"
+
+// BlockIndex is an in-memory map of every known block keyed by hash, so
+// parent lookups during validation and chain walks don't need to hit the
+// store. It is rebuilt from the store on startup and kept in sync as new
+// blocks land.
+type BlockIndex struct {
+	mu     sync.RWMutex
+	blocks map[string]Block
+}
+
+func NewBlockIndex() *BlockIndex {
+	return &BlockIndex{blocks: make(map[string]Block)}
+}
+
+// Add records a block in the index, keyed by its hash.
+func (idx *BlockIndex) Add(b Block) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.blocks[b.Hash] = b
+}
+
+// Get looks up a block by hash.
+func (idx *BlockIndex) Get(hash string) (Block, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	b, ok := idx.blocks[hash]
+	return b, ok
+}
+
+// Parent returns the block that b.PrevHash points to, if known.
+func (idx *BlockIndex) Parent(b Block) (Block, bool) {
+	return idx.Get(b.PrevHash)
+}
+
+// Len reports how many blocks are currently indexed.
+func (idx *BlockIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.blocks)
+}
+
+// OrphanManage buffers blocks whose parent hasn't arrived yet (e.g. received
+// out of order from a peer) and replays them once the parent shows up.
+type OrphanManage struct {
+	mu sync.Mutex
+	// byParent maps a missing parent hash to the orphan blocks waiting on it.
+	byParent map[string][]Block
+}
+
+func NewOrphanManage() *OrphanManage {
+	return &OrphanManage{byParent: make(map[string][]Block)}
+}
+
+// Add buffers an orphan block under the parent hash it is waiting for.
+func (om *OrphanManage) Add(b Block) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.byParent[b.PrevHash] = append(om.byParent[b.PrevHash], b)
+}
+
+// Children pops and returns every orphan waiting on parentHash, removing
+// them from the buffer. Call this once parentHash has landed, then attempt
+// to process each returned block (which may itself unblock further orphans).
+func (om *OrphanManage) Children(parentHash string) []Block {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	children := om.byParent[parentHash]
+	delete(om.byParent, parentHash)
+	return children
+}
+
+// Len reports how many orphan blocks are currently buffered.
+func (om *OrphanManage) Len() int {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	n := 0
+	for _, v := range om.byParent {
+		n += len(v)
+	}
+	return n
+}
@@ -0,0 +1,155 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// MerkleTree retains every intermediate level of the tree (not just the
+// root) so it can produce inclusion proofs for individual transactions.
+// Levels are stored post-duplication, i.e. each level except the root has
+// even length, matching the pairing that actually produced the next level.
+// That padding makes levels[0] itself even-length when the real leaf count
+// is odd, so leafCount separately records the true number of leaves for
+// bounds-checking.
+type MerkleTree struct {
+	levels    [][][]byte
+	leafCount int
+}
+
+// NewMerkleTree builds a tree over plain-string transactions, matching
+// MerkleRoot's hashing rule. Used for legacy blocks (e.g. genesis).
+func NewMerkleTree(txs []string) *MerkleTree {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		h := sha256.Sum256([]byte(tx))
+		leaves[i] = h[:]
+	}
+	return newMerkleTree(leaves)
+}
+
+// NewMerkleTreeTxs builds a tree over signed transactions, matching
+// MerkleRootTxs's hashing rule.
+func NewMerkleTreeTxs(txs []Transaction) *MerkleTree {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		h := sha256.Sum256(tx.CanonicalBytes())
+		leaves[i] = h[:]
+	}
+	return newMerkleTree(leaves)
+}
+
+func newMerkleTree(leaves [][]byte) *MerkleTree {
+	leafCount := len(leaves)
+	if leafCount == 0 {
+		sum := sha256.Sum256([]byte{})
+		return &MerkleTree{levels: [][][]byte{{sum[:]}}, leafCount: 0}
+	}
+
+	level := make([][]byte, len(leaves))
+	for i, h := range leaves {
+		b := make([]byte, len(h))
+		copy(b, h)
+		level[i] = b
+	}
+	levels := [][][]byte{level}
+
+	for len(level) > 1 {
+		// If odd, duplicate last, and record the padded level itself —
+		// that's the one whose pairs actually get hashed together.
+		if len(level)%2 == 1 {
+			dup := make([]byte, len(level[len(level)-1]))
+			copy(dup, level[len(level)-1])
+			level = append(level, dup)
+			levels[len(levels)-1] = level
+		}
+
+		next := make([][]byte, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			combined := append(append([]byte{}, level[i]...), level[i+1]...)
+			sum := sha256.Sum256(combined)
+			b := make([]byte, len(sum[:]))
+			copy(b, sum[:])
+			next = append(next, b)
+		}
+		levels = append(levels, next)
+		level = next
+	}
+
+	return &MerkleTree{levels: levels, leafCount: leafCount}
+}
+
+// Root returns the tree's root hash, hex-encoded.
+func (t *MerkleTree) Root() string {
+	return hex.EncodeToString(t.levels[len(t.levels)-1][0])
+}
+
+// LeafHash returns the hex-encoded leaf hash at txIndex.
+func (t *MerkleTree) LeafHash(txIndex int) (string, error) {
+	if txIndex < 0 || txIndex >= t.leafCount {
+		return "", fmt.Errorf("tx index %d out of range", txIndex)
+	}
+	return hex.EncodeToString(t.levels[0][txIndex]), nil
+}
+
+// ProofNode is one step of a Merkle inclusion proof: the sibling hash to
+// combine with the running hash, and which side it sits on.
+type ProofNode struct {
+	Hash string `json:"hash"`
+	Side string `json:"side"` // "L" or "R"
+}
+
+// Proof returns the inclusion proof for the transaction at txIndex: the
+// sibling hash at each level from the leaf up to (but not including) the
+// root.
+func (t *MerkleTree) Proof(txIndex int) ([]ProofNode, error) {
+	if txIndex < 0 || txIndex >= t.leafCount {
+		return nil, fmt.Errorf("tx index %d out of range", txIndex)
+	}
+
+	proof := make([]ProofNode, 0, len(t.levels)-1)
+	idx := txIndex
+	for lvl := 0; lvl < len(t.levels)-1; lvl++ {
+		level := t.levels[lvl]
+		siblingIdx := idx ^ 1
+		side := "L"
+		if siblingIdx > idx {
+			side = "R"
+		}
+		proof = append(proof, ProofNode{Hash: hex.EncodeToString(level[siblingIdx]), Side: side})
+		idx /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof walks proof from leafHash up to the root, folding
+// SHA256(left||right) at each step, and checks the result equals root.
+func VerifyMerkleProof(leafHash string, proof []ProofNode, root string) bool {
+	cur, err := hex.DecodeString(leafHash)
+	if err != nil {
+		return false
+	}
+
+	for _, node := range proof {
+		sib, err := hex.DecodeString(node.Hash)
+		if err != nil {
+			return false
+		}
+
+		var combined []byte
+		switch node.Side {
+		case "L":
+			combined = append(append([]byte{}, sib...), cur...)
+		case "R":
+			combined = append(append([]byte{}, cur...), sib...)
+		default:
+			return false
+		}
+
+		sum := sha256.Sum256(combined)
+		cur = sum[:]
+	}
+
+	return hex.EncodeToString(cur) == root
+}
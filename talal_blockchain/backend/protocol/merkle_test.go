@@ -0,0 +1,39 @@
+package protocol
+
+import "testing"
+
+func TestMerkleTreeOddLeafCountRejectsOutOfRangeIndex(t *testing.T) {
+	txs := []string{"a", "b", "c"} // odd count: level 0 gets padded to 4
+	tree := NewMerkleTree(txs)
+
+	if _, err := tree.LeafHash(3); err == nil {
+		t.Fatal("LeafHash(3) should error: only 3 real transactions, index 3 is the padding duplicate")
+	}
+	if _, err := tree.Proof(3); err == nil {
+		t.Fatal("Proof(3) should error: only 3 real transactions, index 3 is the padding duplicate")
+	}
+
+	if _, err := tree.LeafHash(2); err != nil {
+		t.Fatalf("LeafHash(2) should succeed for the last real transaction: %v", err)
+	}
+}
+
+func TestMerkleProofRoundTrip(t *testing.T) {
+	txs := []string{"a", "b", "c", "d", "e"}
+	tree := NewMerkleTree(txs)
+	root := tree.Root()
+
+	for i := range txs {
+		leafHash, err := tree.LeafHash(i)
+		if err != nil {
+			t.Fatalf("LeafHash(%d): %v", i, err)
+		}
+		proof, err := tree.Proof(i)
+		if err != nil {
+			t.Fatalf("Proof(%d): %v", i, err)
+		}
+		if !VerifyMerkleProof(leafHash, proof, root) {
+			t.Errorf("proof for tx %d did not verify against root", i)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package protocol
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Transaction is a signed transfer from one wallet to another. From and To
+// are hex-encoded ed25519 public keys.
+type Transaction struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount uint64 `json:"amount"`
+	Nonce  uint64 `json:"nonce"`
+	Sig    []byte `json:"sig"`
+}
+
+// CanonicalBytes is the deterministic encoding of tx that gets signed and
+// hashed — every field except the signature itself.
+func (tx Transaction) CanonicalBytes() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%d|%d", tx.From, tx.To, tx.Amount, tx.Nonce))
+}
+
+// Hash is the hex-encoded SHA-256 of tx's canonical bytes, used as the
+// Merkle leaf and as a transaction identifier.
+func (tx Transaction) Hash() string {
+	sum := sha256.Sum256(tx.CanonicalBytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySignature checks Sig against CanonicalBytes using the From public key.
+func (tx Transaction) VerifySignature() bool {
+	pub, err := hex.DecodeString(tx.From)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(pub), tx.CanonicalBytes(), tx.Sig)
+}
+
+// String renders tx as JSON, used as the backward-compatible projection
+// onto Block.Txs for clients that only understand the old []string shape.
+func (tx Transaction) String() string {
+	raw, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Sprintf("{\"from\":%q,\"to\":%q,\"amount\":%d,\"nonce\":%d}", tx.From, tx.To, tx.Amount, tx.Nonce)
+	}
+	return string(raw)
+}
@@ -0,0 +1,55 @@
+package protocol
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+)
+
+// signedTx builds a transaction signed by a freshly generated keypair.
+func signedTx(t *testing.T, to string, amount, nonce uint64) Transaction {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tx := Transaction{
+		From:   hex.EncodeToString(pub),
+		To:     to,
+		Amount: amount,
+		Nonce:  nonce,
+	}
+	tx.Sig = ed25519.Sign(priv, tx.CanonicalBytes())
+	return tx
+}
+
+func TestVerifySignatureAcceptsHonestTx(t *testing.T) {
+	tx := signedTx(t, "bob", 10, 0)
+	if !tx.VerifySignature() {
+		t.Fatal("expected a properly signed transaction to verify")
+	}
+}
+
+func TestVerifySignatureRejectsTamperedAmount(t *testing.T) {
+	tx := signedTx(t, "bob", 10, 0)
+	tx.Amount = 1000000 // tampered after signing
+	if tx.VerifySignature() {
+		t.Fatal("expected signature verification to fail once the amount was tampered with")
+	}
+}
+
+func TestVerifySignatureRejectsWrongKey(t *testing.T) {
+	tx := signedTx(t, "bob", 10, 0)
+
+	other, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tx.From = hex.EncodeToString(other)
+
+	if tx.VerifySignature() {
+		t.Fatal("expected signature verification to fail against a different public key")
+	}
+}
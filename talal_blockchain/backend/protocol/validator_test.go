@@ -0,0 +1,159 @@
+package protocol
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func mineSimpleChain(t *testing.T, n, difficulty int) []Block {
+	t.Helper()
+
+	gen := NewGenesisBlock("22L-6679", difficulty)
+	chain := []Block{gen}
+	base := time.Now().UTC()
+
+	for i := 1; i <= n; i++ {
+		prev := chain[len(chain)-1]
+		b := Block{
+			Index:      prev.Index + 1,
+			Timestamp:  base.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+			Txs:        []string{},
+			MerkleRoot: MerkleRoot(nil),
+			PrevHash:   prev.Hash,
+			Difficulty: difficulty,
+		}
+		b.Bloom = BlockBloom(b)
+		chain = append(chain, MineBlock(b, difficulty))
+	}
+	return chain
+}
+
+func TestValidateChainAcceptsHonestChain(t *testing.T) {
+	chain := mineSimpleChain(t, 5, 1)
+	v := DefaultValidator{Difficulty: 1}
+	if err := v.ValidateChain(chain); err != nil {
+		t.Fatalf("expected honest chain to validate, got: %v", err)
+	}
+}
+
+func TestValidateHeaderRejectsBrokenPrevHash(t *testing.T) {
+	chain := mineSimpleChain(t, 2, 1)
+	v := DefaultValidator{Difficulty: 1}
+
+	tampered := chain[2]
+	tampered.PrevHash = "not the real parent hash"
+	if err := v.ValidateHeader(chain[1], tampered); err == nil {
+		t.Fatal("expected error for a block whose prevHash doesn't match its parent")
+	}
+}
+
+func TestValidateHeaderRejectsBadProofOfWork(t *testing.T) {
+	chain := mineSimpleChain(t, 2, 1)
+	v := DefaultValidator{Difficulty: 1}
+
+	tampered := chain[2]
+	tampered.Hash = "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" // no leading zero
+	if err := v.ValidateHeader(chain[1], tampered); err == nil {
+		t.Fatal("expected error for a block that doesn't satisfy its recorded difficulty")
+	}
+}
+
+func TestValidateBodyRejectsTamperedMerkleRoot(t *testing.T) {
+	chain := mineSimpleChain(t, 1, 1)
+	v := DefaultValidator{Difficulty: 1}
+
+	tampered := chain[1]
+	tampered.MerkleRoot = "0000000000000000000000000000000000000000000000000000000000000000"
+	if err := v.ValidateBody(tampered); err == nil {
+		t.Fatal("expected error for a merkleRoot that doesn't match the block's txs")
+	}
+}
+
+func TestValidateBodyRejectsDuplicateTxs(t *testing.T) {
+	b := Block{
+		Index:     1,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Txs:       []string{"dup", "dup"},
+		PrevHash:  "0",
+	}
+	b.MerkleRoot = MerkleRoot(b.Txs)
+	b.Bloom = BlockBloom(b)
+	b = MineBlock(b, 0)
+
+	v := DefaultValidator{Difficulty: 0}
+	err := v.ValidateBody(b)
+	if err == nil {
+		t.Fatal("expected error for a block with duplicate transactions")
+	}
+}
+
+func TestValidateChainRejectsEmptyChain(t *testing.T) {
+	v := DefaultValidator{Difficulty: 1}
+	if err := v.ValidateChain(nil); err == nil {
+		t.Fatal("expected error for an empty chain")
+	}
+}
+
+func TestValidateChainReportFlagsOnlyTheBadBlock(t *testing.T) {
+	chain := mineSimpleChain(t, 3, 1)
+	chain[2].Nonce++ // break block 2 only
+
+	v := DefaultValidator{Difficulty: 1}
+	reports := ValidateChainReport(v, chain)
+
+	if len(reports) != len(chain) {
+		t.Fatalf("expected %d reports, got %d", len(chain), len(reports))
+	}
+	for _, rep := range reports {
+		wantValid := rep.Index != 2
+		if rep.Valid != wantValid {
+			t.Errorf("block %d: valid=%v, want %v", rep.Index, rep.Valid, wantValid)
+		}
+	}
+}
+
+// forgedLegacyTxBlock builds a non-genesis block that carries the legacy Txs
+// string projection of a fund transfer without the signed Transaction
+// backing it, as a gossiped peer would if it tried to smuggle in an unsigned
+// transfer.
+func forgedLegacyTxBlock(t *testing.T, index int, prevHash string) Block {
+	t.Helper()
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tx := Transaction{From: hex.EncodeToString(pub), To: "bob", Amount: 1000000, Nonce: 0}
+
+	b := Block{
+		Index:     index,
+		Timestamp: "2024-01-01T00:00:00Z",
+		Txs:       []string{tx.String()},
+		PrevHash:  prevHash,
+	}
+	b.MerkleRoot = MerkleRoot(b.Txs)
+	b.Bloom = BlockBloom(b)
+	return MineBlock(b, 0)
+}
+
+func TestValidateBodyRejectsNonGenesisBlockWithoutSignedTransactions(t *testing.T) {
+	b := forgedLegacyTxBlock(t, 1, "0")
+
+	v := DefaultValidator{Difficulty: 0}
+	if err := v.ValidateBody(b); err == nil {
+		t.Fatal("expected ValidateBody to reject a non-genesis block with legacy txs but no signed Transactions")
+	}
+}
+
+func TestValidateChainRejectsForgedBlockWithoutSignedTransactions(t *testing.T) {
+	gen := NewGenesisBlock("22L-6679", 0)
+	forged := forgedLegacyTxBlock(t, 1, gen.Hash)
+
+	chain := []Block{gen, forged}
+	v := DefaultValidator{Difficulty: 0}
+	if err := v.ValidateChain(chain); err == nil {
+		t.Fatal("expected ValidateChain to reject a forged chain carrying unsigned legacy txs past genesis")
+	}
+}
@@ -0,0 +1,149 @@
+// Package store persists the chain to disk so a node survives a restart,
+// modeled on the block-store/chain-index split used by geth and Bytom:
+// blocks live in LevelDB keyed by hash, with a small set of secondary keys
+// for looking things up by index and for tracking the current head.
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/TalalNad/Blockchain-Intro/talal_blockchain/backend/protocol"
+)
+
+// ErrNotFound is returned when a requested block or header doesn't exist.
+var ErrNotFound = errors.New("store: not found")
+
+// BlockStore is the persistence interface the rest of the node codes
+// against, so the in-memory tests and any future backend swap don't need
+// to touch call sites.
+type BlockStore interface {
+	SaveBlock(b protocol.Block) error
+	GetBlock(hash string) (protocol.Block, error)
+	GetBlockByHash(hash string) (protocol.Block, error)
+	GetHeader(index int) (protocol.Block, error)
+	Head() (protocol.Block, error)
+	Iterate(fn func(b protocol.Block) bool) error
+}
+
+const (
+	blockPrefix  = "b:" // b:<hash>       -> json(Block)
+	heightPrefix = "h:" // h:<index,u64>  -> hash
+	headKey      = "head"
+)
+
+// LevelDBStore is the LevelDB-backed BlockStore implementation.
+type LevelDBStore struct {
+	db *leveldb.DB
+}
+
+// Open opens (creating if necessary) a LevelDB database at dir.
+func Open(dir string) (*LevelDBStore, error) {
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", dir, err)
+	}
+	return &LevelDBStore{db: db}, nil
+}
+
+func (s *LevelDBStore) Close() error {
+	return s.db.Close()
+}
+
+func heightKey(index int) []byte {
+	buf := make([]byte, len(heightPrefix)+8)
+	copy(buf, heightPrefix)
+	binary.BigEndian.PutUint64(buf[len(heightPrefix):], uint64(index))
+	return buf
+}
+
+// SaveBlock writes the block and updates the height index and head pointer.
+// Blocks are expected to be saved in chain order; SaveBlock does not itself
+// validate the block, that's the Validator's job.
+func (s *LevelDBStore) SaveBlock(b protocol.Block) error {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("store: marshal block %d: %w", b.Index, err)
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(blockPrefix+b.Hash), raw)
+	batch.Put(heightKey(b.Index), []byte(b.Hash))
+	batch.Put([]byte(headKey), []byte(b.Hash))
+
+	return s.db.Write(batch, nil)
+}
+
+func (s *LevelDBStore) getByHash(hash string) (protocol.Block, error) {
+	raw, err := s.db.Get([]byte(blockPrefix+hash), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return protocol.Block{}, ErrNotFound
+		}
+		return protocol.Block{}, err
+	}
+	var b protocol.Block
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return protocol.Block{}, fmt.Errorf("store: unmarshal block %s: %w", hash, err)
+	}
+	return b, nil
+}
+
+// GetBlock fetches a block by hash. It's an alias of GetBlockByHash kept for
+// callers that don't care which key they're looking up by.
+func (s *LevelDBStore) GetBlock(hash string) (protocol.Block, error) {
+	return s.getByHash(hash)
+}
+
+func (s *LevelDBStore) GetBlockByHash(hash string) (protocol.Block, error) {
+	return s.getByHash(hash)
+}
+
+// GetHeader fetches a block by chain index (name kept for parity with the
+// height -> hash -> block lookup chain used elsewhere; we don't store a
+// separate lightweight header type yet).
+func (s *LevelDBStore) GetHeader(index int) (protocol.Block, error) {
+	hash, err := s.db.Get(heightKey(index), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return protocol.Block{}, ErrNotFound
+		}
+		return protocol.Block{}, err
+	}
+	return s.getByHash(string(hash))
+}
+
+// Head returns the most recently saved block.
+func (s *LevelDBStore) Head() (protocol.Block, error) {
+	hash, err := s.db.Get([]byte(headKey), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return protocol.Block{}, ErrNotFound
+		}
+		return protocol.Block{}, err
+	}
+	return s.getByHash(string(hash))
+}
+
+// Iterate walks every stored block in index order, calling fn for each.
+// Iteration stops early if fn returns false.
+func (s *LevelDBStore) Iterate(fn func(b protocol.Block) bool) error {
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(heightPrefix)), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		b, err := s.getByHash(string(iter.Value()))
+		if err != nil {
+			return err
+		}
+		if !fn(b) {
+			break
+		}
+	}
+	return iter.Error()
+}
@@ -0,0 +1,54 @@
+// Package wallet generates and manages the ed25519 signing keys used to
+// sign transactions. Keys are exchanged as hex strings over HTTP.
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeyPair is a signing key, kept in memory only — there is no persistence
+// or encryption here, this is a demo wallet, not a production one.
+type KeyPair struct {
+	PublicKey  ed25519.PublicKey
+	PrivateKey ed25519.PrivateKey
+}
+
+// Generate creates a fresh keypair.
+func Generate() (*KeyPair, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("wallet: generate keypair: %w", err)
+	}
+	return &KeyPair{PublicKey: pub, PrivateKey: priv}, nil
+}
+
+func (kp *KeyPair) PublicKeyHex() string  { return hex.EncodeToString(kp.PublicKey) }
+func (kp *KeyPair) PrivateKeyHex() string { return hex.EncodeToString(kp.PrivateKey) }
+
+// Sign signs msg with the keypair's private key.
+func (kp *KeyPair) Sign(msg []byte) []byte {
+	return ed25519.Sign(kp.PrivateKey, msg)
+}
+
+// ParsePrivateKeyHex decodes a hex-encoded private key, such as the one
+// returned by POST /wallet/new, back into a usable KeyPair.
+func ParsePrivateKeyHex(s string) (*KeyPair, error) {
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("wallet: decode private key: %w", err)
+	}
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, errors.New("wallet: private key has the wrong length")
+	}
+
+	priv := ed25519.PrivateKey(raw)
+	pub, ok := priv.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("wallet: could not derive public key")
+	}
+	return &KeyPair{PublicKey: pub, PrivateKey: priv}, nil
+}